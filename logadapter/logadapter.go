@@ -0,0 +1,33 @@
+// Package logadapter adapts common structured loggers to satisfy
+// pbmon.Logger.
+package logadapter
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/rs/zerolog"
+)
+
+// Zerolog adapts a zerolog.Logger to satisfy pbmon.Logger.
+type Zerolog struct {
+	L zerolog.Logger
+}
+
+func (z Zerolog) Infof(format string, args ...interface{})  { z.L.Info().Msgf(format, args...) }
+func (z Zerolog) Errorf(format string, args ...interface{}) { z.L.Error().Msgf(format, args...) }
+
+// Slog adapts a *slog.Logger to satisfy pbmon.Logger.
+type Slog struct {
+	L *slog.Logger
+}
+
+func (s Slog) Infof(format string, args ...interface{})  { s.L.Info(fmt.Sprintf(format, args...)) }
+func (s Slog) Errorf(format string, args ...interface{}) { s.L.Error(fmt.Sprintf(format, args...)) }
+
+// Noop discards every log line. It's useful for tests or for callers who
+// want pbmon to stay completely silent.
+type Noop struct{}
+
+func (Noop) Infof(string, ...interface{})  {}
+func (Noop) Errorf(string, ...interface{}) {}
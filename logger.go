@@ -0,0 +1,28 @@
+package pbmon
+
+import "log"
+
+// Logger is a minimal leveled logging interface pbmon uses internally for
+// diagnostics, so it can fit into whatever observability stack a caller
+// already has. See the logadapter package for zerolog and log/slog
+// adapters.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// stdLogger is the default Logger, backed by the standard log package, so
+// pbmon behaves the same way it always has until a caller opts in to
+// something else via WithLogger.
+type stdLogger struct{}
+
+func (stdLogger) Infof(format string, args ...interface{})  { log.Printf(format, args...) }
+func (stdLogger) Errorf(format string, args ...interface{}) { log.Printf(format, args...) }
+
+// WithLogger overrides the default standard-library logger.
+func WithLogger(l Logger) func(*PastebinMonitor) error {
+	return func(p *PastebinMonitor) error {
+		p.logger = l
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package pbmon
+
+import (
+	"fmt"
+	"regexp"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+
+	"ilya.app/pbmon/rules"
+)
+
+// MatchResult carries everything an OnMatch callback needs about a single
+// rule match found while scanning a paste.
+type MatchResult struct {
+	Rule    string
+	Paste   pastebin.Paste
+	Matches [][]string
+}
+
+// OnMatchFunc handles a single MatchResult produced by a Matcher.
+type OnMatchFunc func(MatchResult) error
+
+type matcherRule struct {
+	name   string
+	re     *regexp.Regexp
+	action func(pastebin.Paste, []byte, [][]string) error
+}
+
+// Matcher evaluates a paste body against a set of regular-expression
+// rules before a monitor calls OnNew, so callers don't have to reimplement
+// the same scanning plumbing on top of the bare OnNew callback.
+type Matcher struct {
+	rules []matcherRule
+
+	// MaxMatches bounds the total number of matches recorded across all
+	// rules for a single paste, so a pathological body can't blow up scan
+	// time. Zero means unlimited.
+	MaxMatches int
+
+	// EntropyWindow and EntropyThreshold, if both non-zero, make Scan also
+	// report high-entropy substrings (e.g. base64/hex-looking secrets) as
+	// matches of a synthetic "high-entropy" rule.
+	EntropyWindow    int
+	EntropyThreshold float64
+}
+
+// NewMatcher returns an empty Matcher.
+func NewMatcher() *Matcher {
+	return &Matcher{}
+}
+
+// AddRule registers a named rule. action, if non-nil, is called once per
+// scanned paste in which re matches, with all of that rule's matches (after
+// any MaxMatches truncation), in addition to the result being included in
+// Scan's return value.
+func (m *Matcher) AddRule(name string, re *regexp.Regexp, action func(pastebin.Paste, []byte, [][]string) error) {
+	m.rules = append(m.rules, matcherRule{name: name, re: re, action: action})
+}
+
+// EnableEntropy turns on the high-entropy check with the given window
+// size and threshold. See EntropyWindow and EntropyThreshold.
+func (m *Matcher) EnableEntropy(window int, threshold float64) {
+	m.EntropyWindow = window
+	m.EntropyThreshold = threshold
+}
+
+// Scan evaluates body against every registered rule plus the entropy check
+// if configured, and returns one MatchResult per rule that matched. It
+// stops evaluating further rules once MaxMatches total matches have been
+// recorded, to bound CPU spent per paste.
+func (m *Matcher) Scan(paste pastebin.Paste, body []byte) ([]MatchResult, error) {
+	var (
+		results []MatchResult
+		total   int
+	)
+
+	for _, r := range m.rules {
+		if m.MaxMatches > 0 && total >= m.MaxMatches {
+			return results, nil
+		}
+
+		matches := r.re.FindAllSubmatch(body, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		if m.MaxMatches > 0 && total+len(matches) > m.MaxMatches {
+			matches = matches[:m.MaxMatches-total]
+		}
+		total += len(matches)
+
+		strMatches := make([][]string, len(matches))
+		for i, match := range matches {
+			groups := make([]string, len(match))
+			for j, g := range match {
+				groups[j] = string(g)
+			}
+			strMatches[i] = groups
+		}
+
+		if r.action != nil {
+			if err := r.action(paste, body, strMatches); err != nil {
+				return results, fmt.Errorf("rule %s action: %w", r.name, err)
+			}
+		}
+
+		results = append(results, MatchResult{Rule: r.name, Paste: paste, Matches: strMatches})
+	}
+
+	if m.EntropyWindow > 0 && m.EntropyThreshold > 0 && (m.MaxMatches == 0 || total < m.MaxMatches) {
+		windows := rules.HighEntropyWindows(body, m.EntropyWindow, m.EntropyThreshold)
+		if m.MaxMatches > 0 && total+len(windows) > m.MaxMatches {
+			windows = windows[:m.MaxMatches-total]
+		}
+		if len(windows) > 0 {
+			matches := make([][]string, len(windows))
+			for i, w := range windows {
+				matches[i] = []string{w}
+			}
+			results = append(results, MatchResult{Rule: "high-entropy", Paste: paste, Matches: matches})
+		}
+	}
+
+	return results, nil
+}
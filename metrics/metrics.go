@@ -0,0 +1,63 @@
+// Package metrics exposes the Prometheus collectors pbmon reports
+// against when a monitor is configured with WithMetricsRegistry.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const namespace = "pbmon"
+
+// Metrics bundles every collector pbmon reports against.
+type Metrics struct {
+	PastesFetched prometheus.Counter
+	HTTPStatus    *prometheus.CounterVec
+	GetRawLatency prometheus.Histogram
+	RuleMatches   *prometheus.CounterVec
+	SinkErrors    prometheus.Counter
+	PastesDeleted prometheus.Counter
+}
+
+// New creates and registers every collector against reg.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		PastesFetched: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pastes_fetched_total",
+			Help:      "Total number of pastes fetched from scrape.pastebin.com.",
+		}),
+		HTTPStatus: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_responses_total",
+			Help:      "HTTP responses from scrape.pastebin.com, by status code.",
+		}, []string{"code"}),
+		GetRawLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "get_raw_duration_seconds",
+			Help:      "Latency of fetching a single paste's raw body.",
+		}),
+		RuleMatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rule_matches_total",
+			Help:      "Matcher rule matches, by rule name.",
+		}, []string{"rule"}),
+		SinkErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sink_publish_errors_total",
+			Help:      "Total number of paste publishes that failed for at least one sink.",
+		}),
+		PastesDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pastes_deleted_total",
+			Help:      "Total number of archived pastes removed by the retention cleanup task.",
+		}),
+	}
+
+	reg.MustRegister(
+		m.PastesFetched,
+		m.HTTPStatus,
+		m.GetRawLatency,
+		m.RuleMatches,
+		m.SinkErrors,
+		m.PastesDeleted,
+	)
+	return m
+}
@@ -4,36 +4,105 @@
 package pbmon
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	pastebin "github.com/dutchcoders/gopastebin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/time/rate"
+
+	"ilya.app/pbmon/metrics"
+	"ilya.app/pbmon/store/local"
 )
 
 const (
 	DefaultRecentSize       = 50 // Amount of pastes to get on one request
 	defaultEvictionDuration = 10 * time.Minute
-	pasteIDLen              = 9
 	DefaultTimeout          = 10 * time.Second // Timeout between poll requests.
 )
 
 // OnNewPaste is a callback function for processing a new paste.
 type OnNewPaste func(pastebin.Paste, io.ReadCloser) error
 
+// StateStore persists the last-seen paste key so a monitor can resume
+// where it left off across restarts. See the store/local, store/postgres
+// and store/s3 packages for ready-made implementations.
+type StateStore interface {
+	Load(ctx context.Context) (string, error)
+	Save(ctx context.Context, key string) error
+}
+
+// PasteStore archives the body of every paste a monitor sees. It is
+// separate from StateStore because "where did I stop" and "what did I
+// see" are independent concerns: a monitor can track state without
+// archiving, or archive without ever needing to resume.
+type PasteStore interface {
+	Put(ctx context.Context, paste pastebin.Paste, body io.Reader) error
+
+	// DeleteOlderThan removes archived pastes older than cutoff, returning
+	// the number removed. It backs the retention cleanup task enabled by
+	// WithRetention.
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// ProgressReporter reports fetch progress while a monitor's worker pool
+// processes a batch of pastes. See the progress package for a default
+// multi-bar implementation.
+type ProgressReporter interface {
+	// Start is called once per batch with the number of pastes to fetch.
+	Start(total int)
+	// Fetched is called by worker after it finishes fetching one paste.
+	Fetched(worker int)
+	// Finish is called once the batch has been fully processed.
+	Finish()
+}
+
+type noopProgress struct{}
+
+func (noopProgress) Start(int)   {}
+func (noopProgress) Fetched(int) {}
+func (noopProgress) Finish()     {}
+
 // PastebinMonitor handles monitoring of the pastebin.com
 type PastebinMonitor struct {
-	topKey    string
-	timeout   time.Duration
-	pbClient  *pastebin.PastebinClient
-	stateFile *os.File
-	OnNew     OnNewPaste
+	topKey     string
+	timeout    time.Duration
+	pbClient   *pastebin.PastebinClient
+	stateStore StateStore
+	pasteStore PasteStore
+	OnNew      OnNewPaste
+
+	// Matcher, if set, scans every paste body against its rules before
+	// OnNew is called, reporting matches via OnMatch.
+	Matcher *Matcher
+	OnMatch OnMatchFunc
+
+	concurrency int
+	limiter     *rate.Limiter
+	progress    ProgressReporter
+
+	sinks []Sink
+
+	retentionLifetime time.Duration
+	retentionInterval time.Duration
+	deletedCount      int64
+
+	logger   Logger
+	registry *prometheus.Registry
+	metrics  *metrics.Metrics
 }
 
 // New constructs a pastebin monitor.
@@ -42,12 +111,15 @@ func New(opts ...func(*PastebinMonitor) error) (*PastebinMonitor, error) {
 	pc := pastebin.New(baseURL)
 
 	conf := &PastebinMonitor{
-		pbClient: pc,
-		timeout:  DefaultTimeout,
-		OnNew: func(p pastebin.Paste, r io.ReadCloser) error {
-			log.Printf("title=%s user=%s syntax=%s url=%s ", p.Title, p.User, p.Syntax, p.FullURL)
-			return nil
-		},
+		pbClient:    pc,
+		timeout:     DefaultTimeout,
+		concurrency: 1,
+		progress:    noopProgress{},
+		logger:      stdLogger{},
+	}
+	conf.OnNew = func(p pastebin.Paste, r io.ReadCloser) error {
+		conf.logger.Infof("title=%s user=%s syntax=%s url=%s ", p.Title, p.User, p.Syntax, p.FullURL)
+		return nil
 	}
 
 	for _, f := range opts {
@@ -61,61 +133,137 @@ func New(opts ...func(*PastebinMonitor) error) (*PastebinMonitor, error) {
 }
 
 func (p *PastebinMonitor) loadState() error {
-	var err error
+	if p.stateStore == nil {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("get home directory: %w", err)
+		}
 
-	if p.stateFile != nil {
-		p.topKey, err = readState(p.stateFile)
+		ss, err := local.NewStateStore(filepath.Join(home, ".pbmon"))
 		if err != nil {
 			return err
 		}
-		return nil
+		p.stateStore = ss
 	}
 
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("get home directory: %w", err)
-	}
-	stateFileName := filepath.Join(home, ".pbmon")
-
-	f, err := os.OpenFile(stateFileName, os.O_RDWR|os.O_CREATE, 0744)
-	p.topKey, err = readState(f)
+	key, err := p.stateStore.Load(context.Background())
 	if err != nil {
 		return err
 	}
 
-	p.stateFile = f
-	return err
-}
-
-func readState(r io.Reader) (string, error) {
-	top := make([]byte, pasteIDLen)
-	_, err := r.Read(top)
-	if err == io.EOF {
-		return "", nil
-	}
-	if err != nil {
-		return "", fmt.Errorf("read state file: %w", err)
-	}
-	return string(top), nil
+	p.topKey = key
+	return nil
 }
 
 // SetStateFile to be able to resume execution on the last paste and achieve
-// persistence.
+// persistence. It is a shorthand for WithStateStore backed by a local
+// file; use WithStateStore directly for other backends.
 func SetStateFile(fullLoc string) func(*PastebinMonitor) error {
-	f, err := os.OpenFile(fullLoc, os.O_RDWR|os.O_CREATE, 0755)
+	ss, err := local.NewStateStore(fullLoc)
 	if err != nil {
 		return func(p *PastebinMonitor) error {
 			return err
 		}
 	}
+	return WithStateStore(ss)
+}
+
+// WithStateStore overrides the default local-file state store. If used
+// together with SetStateFile, whichever option runs last wins.
+func WithStateStore(ss StateStore) func(*PastebinMonitor) error {
+	return func(p *PastebinMonitor) error {
+		p.stateStore = ss
+		return nil
+	}
+}
+
+// WithPasteStore enables archiving of every paste this monitor sees.
+func WithPasteStore(ps PasteStore) func(*PastebinMonitor) error {
+	return func(p *PastebinMonitor) error {
+		p.pasteStore = ps
+		return nil
+	}
+}
+
+// WithConcurrency sets the number of worker goroutines used to fetch paste
+// bodies. The default is 1 (fully sequential, matching pbmon's original
+// behavior).
+func WithConcurrency(n int) func(*PastebinMonitor) error {
+	return func(p *PastebinMonitor) error {
+		if n < 1 {
+			return fmt.Errorf("concurrency must be at least 1, got %d", n)
+		}
+		p.concurrency = n
+		return nil
+	}
+}
+
+// WithRateLimit caps paste body fetches to rps requests per second, with
+// bursts of up to burst. scrape.pastebin.com is heavily rate-limited, so
+// this should usually be set together with WithConcurrency.
+func WithRateLimit(rps float64, burst int) func(*PastebinMonitor) error {
+	return func(p *PastebinMonitor) error {
+		p.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+		return nil
+	}
+}
+
+// WithProgressReporter reports fetch progress for each batch of pastes.
+// See the progress package for a default multi-bar implementation.
+func WithProgressReporter(r ProgressReporter) func(*PastebinMonitor) error {
+	return func(p *PastebinMonitor) error {
+		p.progress = r
+		return nil
+	}
+}
+
+// WithRetention starts a background task, once Do is running, that
+// deletes archived pastes older than lifetime every interval. It has no
+// effect unless a PasteStore is also configured.
+func WithRetention(lifetime, interval time.Duration) func(*PastebinMonitor) error {
+	return func(p *PastebinMonitor) error {
+		p.retentionLifetime = lifetime
+		p.retentionInterval = interval
+		return nil
+	}
+}
+
+// DeletedCount returns the number of archived pastes removed so far by
+// the retention cleanup task.
+func (p *PastebinMonitor) DeletedCount() int64 {
+	return atomic.LoadInt64(&p.deletedCount)
+}
+
+// WithMetricsRegistry enables Prometheus instrumentation, registering
+// pbmon's collectors (pastes fetched, HTTP status codes, GetRaw latency,
+// rule matches, sink errors, pastes deleted) against reg.
+func WithMetricsRegistry(reg *prometheus.Registry) func(*PastebinMonitor) error {
 	return func(p *PastebinMonitor) error {
-		p.stateFile = f
+		p.registry = reg
+		p.metrics = metrics.New(reg)
 		return nil
 	}
 }
 
+// ServeMetrics starts a minimal HTTP server exposing the Prometheus
+// metrics registered via WithMetricsRegistry at /metrics on addr. It
+// blocks; run it in its own goroutine.
+func (p *PastebinMonitor) ServeMetrics(addr string) error {
+	if p.registry == nil {
+		return errors.New("metrics not configured: use WithMetricsRegistry")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{}))
+	return http.ListenAndServe(addr, mux)
+}
+
 // Do starts fetching new pastes.
 func (p *PastebinMonitor) Do(recentSize int, timeout time.Duration) error {
+	if p.pasteStore != nil && p.retentionInterval > 0 {
+		go p.cleanupLoop()
+	}
+
 	err := p.do(recentSize, timeout)
 	if err != nil {
 		return err
@@ -131,36 +279,140 @@ func (p *PastebinMonitor) Do(recentSize int, timeout time.Duration) error {
 	return nil
 }
 
+// cleanupLoop periodically deletes archived pastes older than
+// retentionLifetime, every retentionInterval. It has no stop condition: it
+// keeps running after Do returns.
+func (p *PastebinMonitor) cleanupLoop() {
+	t := time.NewTicker(p.retentionInterval)
+	defer t.Stop()
+
+	for range t.C {
+		cutoff := time.Now().Add(-p.retentionLifetime)
+		n, err := p.pasteStore.DeleteOlderThan(context.Background(), cutoff)
+		if err != nil {
+			p.logger.Errorf("retention cleanup: %v", err)
+			continue
+		}
+		atomic.AddInt64(&p.deletedCount, int64(n))
+		if p.metrics != nil {
+			p.metrics.PastesDeleted.Add(float64(n))
+		}
+	}
+}
+
+type fetchJob struct {
+	idx   int
+	paste pastebin.Paste
+}
+
+type fetchResult struct {
+	idx int
+	err error
+}
+
 func (p *PastebinMonitor) do(recentSize int, timeout time.Duration) error {
 	pastes, err := p.fetchNewPastes(recentSize)
 	if err != nil {
 		return fmt.Errorf("fetch pastes: %w", err)
 	}
+	if len(pastes) == 0 {
+		return nil
+	}
 
-	for i := len(pastes) - 1; i >= 0; i-- {
-		err := p.processPaste(pastes[i])
-		if err != nil {
-			return fmt.Errorf("process paste: %w", err)
-		}
+	// pastes arrives newest-first; ordered is oldest-first so state can
+	// only advance once every older paste has been acked.
+	ordered := make([]pastebin.Paste, len(pastes))
+	for i, paste := range pastes {
+		ordered[len(pastes)-1-i] = paste
+	}
 
-		err = p.stateFile.Truncate(0)
-		if err != nil {
-			return fmt.Errorf("truncate %s: %w", p.stateFile.Name(), err)
-		}
+	p.progress.Start(len(ordered))
+	defer p.progress.Finish()
+
+	jobs := make(chan fetchJob)
+	results := make(chan fetchResult)
+
+	var wg sync.WaitGroup
+	for w := 0; w < p.concurrency; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := range jobs {
+				if p.limiter != nil {
+					if err := p.limiter.Wait(context.Background()); err != nil {
+						results <- fetchResult{idx: j.idx, err: err}
+						continue
+					}
+				}
+				err := p.processPaste(j.paste)
+				p.progress.Fetched(worker)
+				results <- fetchResult{idx: j.idx, err: err}
+			}
+		}(w)
+	}
 
-		_, err = p.stateFile.Seek(0, 0)
-		if err != nil {
-			return fmt.Errorf("seek to the beginning of %s: %w", p.stateFile.Name(), err)
+	go func() {
+		for i, paste := range ordered {
+			jobs <- fetchJob{idx: i, paste: paste}
 		}
+		close(jobs)
+	}()
 
-		_, err = p.stateFile.WriteString(pastes[i].Key)
-		if err != nil {
-			return fmt.Errorf("save state to %s: %w", p.stateFile.Name(), err)
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return p.ackResults(ordered, results)
+}
+
+// ackResults collects fetch results as they arrive (possibly out of
+// order) and advances state only once every paste up to and including the
+// next unacked index has succeeded, so topKey never skips ahead of a
+// paste that's still in flight or that failed to process. Once the next
+// index fails, it stays pinned there: that paste, and everything after
+// it, is left unacked so it gets retried on the next poll instead of
+// being silently skipped.
+func (p *PastebinMonitor) ackResults(ordered []pastebin.Paste, results <-chan fetchResult) error {
+	pending := make(map[int]error)
+	next := 0
+	var firstErr error
+	stuck := false
+
+	for res := range results {
+		pending[res.idx] = res.err
+		if stuck {
+			continue
 		}
 
-		p.topKey = pastes[i].Key
+		for {
+			err, ok := pending[next]
+			if !ok {
+				break
+			}
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("process paste: %w", err)
+				}
+				stuck = true
+				break
+			}
+
+			delete(pending, next)
+			if saveErr := p.stateStore.Save(context.Background(), ordered[next].Key); saveErr != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("save state: %w", saveErr)
+				}
+				stuck = true
+				break
+			}
+			p.topKey = ordered[next].Key
+			next++
+		}
 	}
-	return nil
+
+	return firstErr
 }
 
 func (p *PastebinMonitor) fetchNewPastes(recentSize int) ([]pastebin.Paste, error) {
@@ -188,12 +440,71 @@ func (p *PastebinMonitor) fetchNewPastes(recentSize int) ([]pastebin.Paste, erro
 }
 
 func (p *PastebinMonitor) processPaste(paste pastebin.Paste) error {
+	start := time.Now()
 	body, err := p.pbClient.GetRaw(paste.Key)
+	if p.metrics != nil {
+		p.metrics.GetRawLatency.Observe(time.Since(start).Seconds())
+	}
 	if err != nil {
 		return fmt.Errorf("pastebin.GetRaw: %w", err)
 	}
+	if p.metrics != nil {
+		p.metrics.PastesFetched.Inc()
+	}
+
+	if p.pasteStore == nil && p.Matcher == nil && len(p.sinks) == 0 {
+		return p.OnNew(paste, body)
+	}
+	defer body.Close()
+
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("read paste body: %w", err)
+	}
 
-	return p.OnNew(paste, body)
+	if p.pasteStore != nil {
+		if err := p.pasteStore.Put(context.Background(), paste, bytes.NewReader(raw)); err != nil {
+			return fmt.Errorf("archive paste %s: %w", paste.Key, err)
+		}
+	}
+
+	// Sink delivery is independent of Matcher/OnNew, and independent of
+	// the poll's ack/retry state machine: a failing external sink
+	// (webhook down, Kafka unreachable, ...) must not suppress local
+	// processing of the paste, and must not pin topKey in ackResults the
+	// way a genuine fetch/process failure does (that would mean a sink
+	// that's down forever, or one that's exhausted its dead-letter
+	// retries, blocks all further progress). So it's logged and metriced
+	// here, not returned.
+	if len(p.sinks) > 0 {
+		if err := NewMultiSink(p.sinks...).Publish(context.Background(), paste, raw); err != nil {
+			if p.metrics != nil {
+				p.metrics.SinkErrors.Inc()
+			}
+			p.logger.Errorf("publish paste %s to sinks: %v", paste.Key, err)
+		}
+	}
+
+	if p.Matcher != nil {
+		results, err := p.Matcher.Scan(paste, raw)
+		if err != nil {
+			return fmt.Errorf("scan paste %s: %w", paste.Key, err)
+		}
+		if p.metrics != nil {
+			for _, result := range results {
+				p.metrics.RuleMatches.WithLabelValues(result.Rule).Inc()
+			}
+		}
+		if p.OnMatch != nil {
+			for _, result := range results {
+				if err := p.OnMatch(result); err != nil {
+					return fmt.Errorf("handle match for %s: %w", paste.Key, err)
+				}
+			}
+		}
+	}
+
+	return p.OnNew(paste, io.NopCloser(bytes.NewReader(raw)))
 }
 
 func (p *PastebinMonitor) recent(size int) ([]pastebin.Paste, error) {
@@ -208,6 +519,10 @@ func (p *PastebinMonitor) recent(size int) ([]pastebin.Paste, error) {
 	}
 	defer resp.Body.Close()
 
+	if p.metrics != nil {
+		p.metrics.HTTPStatus.WithLabelValues(strconv.Itoa(resp.StatusCode)).Inc()
+	}
+
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("%s returned status code %d", req.URL, resp.StatusCode)
 	}
@@ -0,0 +1,57 @@
+package pbmon
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+type countingPasteStore struct {
+	n     int
+	calls int
+	once  sync.Once
+	done  chan struct{}
+}
+
+func (s *countingPasteStore) Put(ctx context.Context, paste pastebin.Paste, body io.Reader) error {
+	return nil
+}
+
+// DeleteOlderThan closes done on its second call: cleanupLoop adds a
+// result to the total only after the call returns, so by the second call
+// the first result has been counted.
+func (s *countingPasteStore) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	s.calls++
+	if s.calls >= 2 {
+		s.once.Do(func() { close(s.done) })
+	}
+	return s.n, nil
+}
+
+// TestCleanupLoopCountsDeletions verifies that cleanupLoop adds every
+// DeleteOlderThan result to the running total exposed by DeletedCount.
+func TestCleanupLoopCountsDeletions(t *testing.T) {
+	ps := &countingPasteStore{n: 3, done: make(chan struct{})}
+	p := &PastebinMonitor{
+		pasteStore:        ps,
+		retentionLifetime: time.Hour,
+		retentionInterval: 5 * time.Millisecond,
+		logger:            stdLogger{},
+	}
+
+	go p.cleanupLoop()
+
+	select {
+	case <-ps.done:
+	case <-time.After(time.Second):
+		t.Fatal("cleanupLoop never called DeleteOlderThan")
+	}
+
+	if got := p.DeletedCount(); got < 3 {
+		t.Fatalf("DeletedCount() = %d, want at least 3", got)
+	}
+}
@@ -0,0 +1,47 @@
+package pbmon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+type memStateStore struct {
+	saved []string
+}
+
+func (m *memStateStore) Load(ctx context.Context) (string, error) { return "", nil }
+
+func (m *memStateStore) Save(ctx context.Context, key string) error {
+	m.saved = append(m.saved, key)
+	return nil
+}
+
+// TestAckResultsStopsAtFailedIndex verifies that a failure at an index
+// doesn't let a later, already-succeeded index advance topKey past it:
+// the failed paste (and anything after it) must stay unacked so it's
+// retried on the next poll.
+func TestAckResultsStopsAtFailedIndex(t *testing.T) {
+	ordered := []pastebin.Paste{{Key: "a"}, {Key: "b"}, {Key: "c"}}
+	ss := &memStateStore{}
+	p := &PastebinMonitor{stateStore: ss}
+
+	results := make(chan fetchResult, len(ordered))
+	results <- fetchResult{idx: 0, err: nil}
+	results <- fetchResult{idx: 2, err: nil}
+	results <- fetchResult{idx: 1, err: errors.New("fetch failed")}
+	close(results)
+
+	if err := p.ackResults(ordered, results); err == nil {
+		t.Fatal("ackResults() error = nil, want non-nil")
+	}
+
+	if want := []string{"a"}; len(ss.saved) != len(want) || ss.saved[0] != want[0] {
+		t.Fatalf("saved = %v, want %v", ss.saved, want)
+	}
+	if p.topKey != "a" {
+		t.Fatalf("topKey = %q, want %q", p.topKey, "a")
+	}
+}
@@ -0,0 +1,71 @@
+// Package progress provides a default multi-bar ProgressReporter for
+// pbmon, built on cheggaaa/pb, so concurrent scrapes can show per-worker
+// throughput without every caller wiring up their own bars.
+package progress
+
+import (
+	"fmt"
+	"sync"
+
+	pb "github.com/cheggaaa/pb/v3"
+)
+
+// MultiBar renders one progress bar per worker plus a total bar, updated
+// as pastes are fetched. It satisfies pbmon.ProgressReporter.
+type MultiBar struct {
+	mu      sync.Mutex
+	pool    *pb.Pool
+	bars    []*pb.ProgressBar
+	total   *pb.ProgressBar
+	workers int
+}
+
+// NewMultiBar returns a MultiBar with one bar per worker, for a pool of
+// the given size.
+func NewMultiBar(workers int) *MultiBar {
+	return &MultiBar{workers: workers}
+}
+
+// Start renders the bars and sets the total count to track.
+func (m *MultiBar) Start(total int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total = pb.New(total)
+	m.total.Set(pb.Bytes, false)
+	m.bars = make([]*pb.ProgressBar, m.workers)
+	bars := make([]*pb.ProgressBar, 0, m.workers+1)
+	bars = append(bars, m.total)
+
+	for i := range m.bars {
+		m.bars[i] = pb.New(0)
+		m.bars[i].SetTemplateString(fmt.Sprintf("worker %d: {{counters . }}", i))
+		bars = append(bars, m.bars[i])
+	}
+
+	m.pool, _ = pb.StartPool(bars...)
+}
+
+// Fetched records one paste fetched by worker, advancing its bar and the
+// total bar.
+func (m *MultiBar) Fetched(worker int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if worker >= 0 && worker < len(m.bars) {
+		m.bars[worker].Increment()
+	}
+	if m.total != nil {
+		m.total.Increment()
+	}
+}
+
+// Finish stops rendering the bars.
+func (m *MultiBar) Finish() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pool != nil {
+		m.pool.Stop()
+	}
+}
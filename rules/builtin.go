@@ -0,0 +1,40 @@
+package rules
+
+import (
+	"regexp"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+var (
+	// AWSAccessKey matches AWS access key IDs (AKIA/ASIA-prefixed).
+	AWSAccessKey = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+
+	// PrivateKeyHeader matches the header line of a PEM-encoded private
+	// key.
+	PrivateKeyHeader = regexp.MustCompile(`-----BEGIN (?:RSA |EC |OPENSSH |DSA )?PRIVATE KEY-----`)
+
+	// JWT matches the three dot-separated base64url segments of a JSON
+	// Web Token.
+	JWT = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\b`)
+)
+
+// RuleAdder is satisfied by *pbmon.Matcher. It is declared here instead of
+// imported to avoid a dependency cycle between this package and pbmon.
+type RuleAdder interface {
+	AddRule(name string, re *regexp.Regexp, action func(pastebin.Paste, []byte, [][]string) error)
+	EnableEntropy(window int, threshold float64)
+}
+
+// Register adds pbmon's built-in secret-pattern rules (AWS access keys,
+// PEM private key headers, JWTs) to m, and enables the entropy check with
+// DefaultEntropyWindow/DefaultEntropyThreshold — the primary real-world
+// use case, since most leaked secrets don't match a fixed pattern. Each
+// regex rule has no action attached; react to matches via the monitor's
+// OnMatch callback instead.
+func Register(m RuleAdder) {
+	m.AddRule("aws-access-key", AWSAccessKey, nil)
+	m.AddRule("private-key-header", PrivateKeyHeader, nil)
+	m.AddRule("jwt", JWT, nil)
+	m.EnableEntropy(DefaultEntropyWindow, DefaultEntropyThreshold)
+}
@@ -0,0 +1,77 @@
+// Package rules provides built-in Matcher rule bundles for common secret
+// patterns, a Shannon-entropy scanner for flagging high-entropy strings,
+// and a loader for YAML/JSON rule files.
+package rules
+
+import (
+	"math"
+	"regexp"
+)
+
+// candidateRun matches a contiguous run of base64/hex-looking characters:
+// the alphabet used by both standard and URL-safe base64, which is a
+// superset of hex. Scanning is restricted to these runs so entropy
+// windows don't get reported inside ordinary prose.
+var candidateRun = regexp.MustCompile(`[A-Za-z0-9+/=_-]+`)
+
+const (
+	// DefaultEntropyWindow is the sliding-window size, in characters, used
+	// to scan for high-entropy (likely base64/hex secret) substrings.
+	DefaultEntropyWindow = 40
+
+	// DefaultEntropyThreshold is the Shannon entropy, in bits per
+	// character, at or above which a window is considered high-entropy.
+	DefaultEntropyThreshold = 4.5
+)
+
+// ShannonEntropy returns the Shannon entropy, in bits per character, of s.
+func ShannonEntropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+
+	n := float64(len(s))
+	var entropy float64
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// HighEntropyWindows slides a window of size chars over every
+// base64/hex-looking run in data (see candidateRun) and returns the whole
+// run, once, for every run that contains at least one high-entropy
+// window. Reporting per-run rather than per-window keeps one long secret
+// from being split into several fragments just because entropy dips
+// below threshold for an individual window partway through it. It is
+// used to flag base64/hex-looking secrets that regex rules would miss.
+func HighEntropyWindows(data []byte, size int, threshold float64) []string {
+	if size <= 0 {
+		return nil
+	}
+
+	var found []string
+	for _, loc := range candidateRun.FindAllIndex(data, -1) {
+		run := string(data[loc[0]:loc[1]])
+		if len(run) < size {
+			continue
+		}
+
+		for i := 0; i+size <= len(run); i++ {
+			if ShannonEntropy(run[i:i+size]) >= threshold {
+				found = append(found, run)
+				break
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShannonEntropy(t *testing.T) {
+	if got := ShannonEntropy(""); got != 0 {
+		t.Errorf("ShannonEntropy(%q) = %v, want 0", "", got)
+	}
+
+	if got := ShannonEntropy("aaaaaaaa"); got != 0 {
+		t.Errorf("ShannonEntropy of a repeated character = %v, want 0", got)
+	}
+
+	// "abcd" uses 4 distinct, evenly-distributed bytes: exactly 2 bits/char.
+	if got := ShannonEntropy("abcd"); got != 2 {
+		t.Errorf(`ShannonEntropy("abcd") = %v, want 2`, got)
+	}
+}
+
+func TestHighEntropyWindowsMergesOverlappingHits(t *testing.T) {
+	secret := "aGVsbG93b3JsZHRoaXNpc2FzZWNyZXRrZXkxMjM0NTY3ODkwYWJjZGVm"
+	text := "prefix before " + secret + " suffix after"
+
+	found := HighEntropyWindows([]byte(text), 8, 3.0)
+	if len(found) != 1 {
+		t.Fatalf("len(found) = %d, want 1 (overlapping windows should collapse into one finding per run)", len(found))
+	}
+	if !strings.Contains(secret, found[0]) {
+		t.Fatalf("found[0] = %q, want a substring of %q", found[0], secret)
+	}
+}
+
+func TestHighEntropyWindowsIgnoresLowEntropyRuns(t *testing.T) {
+	text := "prefix before aaaaaaaaaaaaaaaaaaaa suffix after"
+
+	if found := HighEntropyWindows([]byte(text), 8, 3.0); len(found) != 0 {
+		t.Fatalf("found = %v, want none for a low-entropy run", found)
+	}
+}
+
+func TestHighEntropyWindowsSkipsShortRuns(t *testing.T) {
+	text := "not even base64-like but contains short1 runs"
+
+	if found := HighEntropyWindows([]byte(text), 40, DefaultEntropyThreshold); found != nil {
+		t.Fatalf("found = %v, want nil when no run reaches the window size", found)
+	}
+}
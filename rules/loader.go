@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is a rule as read from a YAML or JSON rule file: a name and a
+// regular-expression pattern, with no Go callback attached.
+type Definition struct {
+	Name    string `json:"name" yaml:"name"`
+	Pattern string `json:"pattern" yaml:"pattern"`
+}
+
+// LoadFile reads rule definitions from a YAML (.yml/.yaml) or JSON (.json)
+// file.
+func LoadFile(path string) ([]Definition, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rule file %s: %w", path, err)
+	}
+
+	var defs []Definition
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(raw, &defs)
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(raw, &defs)
+	default:
+		return nil, fmt.Errorf("unsupported rule file extension for %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse rule file %s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// RegisterFile loads rule definitions from path and adds them to m,
+// compiling each pattern as a regexp. Rules loaded this way have no
+// action attached; react to matches via the monitor's OnMatch callback.
+func RegisterFile(m RuleAdder, path string) error {
+	defs, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, def := range defs {
+		re, err := regexp.Compile(def.Pattern)
+		if err != nil {
+			return fmt.Errorf("compile rule %s: %w", def.Name, err)
+		}
+		m.AddRule(def.Name, re, nil)
+	}
+	return nil
+}
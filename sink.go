@@ -0,0 +1,157 @@
+package pbmon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+// Sink publishes a paste a monitor has seen somewhere else: a queue, a
+// database, a webhook. It lets pbmon act as the ingestion tier of a
+// larger pipeline without every consumer reimplementing the same
+// HTTP/queue plumbing inside OnNew. See the sink package for built-in
+// adapters.
+type Sink interface {
+	Publish(ctx context.Context, paste pastebin.Paste, body []byte) error
+}
+
+// MultiSink fans a single Publish call out to every registered Sink,
+// continuing on to the rest even if one fails.
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink returns a MultiSink that fans out to sinks.
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Publish calls Publish on every sink, returning the combined error of any
+// that failed.
+func (m *MultiSink) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.Publish(ctx, paste, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AddSink registers an additional sink to fan new pastes out to, on top
+// of OnNew.
+func (p *PastebinMonitor) AddSink(s Sink) {
+	p.sinks = append(p.sinks, s)
+}
+
+// RetryPolicy configures retry-with-backoff behavior for a Sink wrapped
+// with WithRetry.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times Publish is tried before
+	// giving up. Must be at least 1.
+	MaxAttempts int
+
+	// InitialDelay is the backoff before the second attempt; it doubles
+	// after every subsequent failed attempt, capped at MaxDelay.
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+
+	// DeadLetterFile, if set, receives one JSON line per paste that
+	// exhausted MaxAttempts, so nothing is silently dropped.
+	DeadLetterFile string
+}
+
+type retryingSink struct {
+	Sink
+	policy RetryPolicy
+}
+
+// WithRetry wraps s so that failed publishes are retried with exponential
+// backoff according to policy, falling back to policy.DeadLetterFile once
+// attempts are exhausted. policy.MaxAttempts must be at least 1; if it
+// isn't, the returned Sink fails every Publish with a descriptive error
+// instead of silently never calling s.
+func WithRetry(s Sink, policy RetryPolicy) Sink {
+	if policy.MaxAttempts < 1 {
+		return invalidRetrySink{err: fmt.Errorf("pbmon: RetryPolicy.MaxAttempts must be at least 1, got %d", policy.MaxAttempts)}
+	}
+	return &retryingSink{Sink: s, policy: policy}
+}
+
+// invalidRetrySink rejects every Publish with a fixed error. It's what
+// WithRetry returns for a misconfigured RetryPolicy, so a bad MaxAttempts
+// fails loudly at publish time instead of panicking or silently dropping
+// pastes.
+type invalidRetrySink struct {
+	err error
+}
+
+func (s invalidRetrySink) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	return s.err
+}
+
+func (r *retryingSink) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	delay := r.policy.InitialDelay
+	var err error
+
+	for attempt := 1; attempt <= r.policy.MaxAttempts; attempt++ {
+		if err = r.Sink.Publish(ctx, paste, body); err == nil {
+			return nil
+		}
+		if attempt == r.policy.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		delay *= 2
+		if r.policy.MaxDelay > 0 && delay > r.policy.MaxDelay {
+			delay = r.policy.MaxDelay
+		}
+	}
+
+	if r.policy.DeadLetterFile != "" {
+		if dlErr := appendDeadLetter(r.policy.DeadLetterFile, paste, body, err); dlErr != nil {
+			return fmt.Errorf("sink publish failed after %d attempts (%v) and dead-letter write failed: %w", r.policy.MaxAttempts, err, dlErr)
+		}
+	}
+	return fmt.Errorf("sink publish failed after %d attempts: %w", r.policy.MaxAttempts, err)
+}
+
+func appendDeadLetter(path string, paste pastebin.Paste, body []byte, cause error) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open dead-letter file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	errMsg := "unknown error"
+	if cause != nil {
+		errMsg = cause.Error()
+	}
+
+	entry := struct {
+		Paste pastebin.Paste `json:"paste"`
+		Body  []byte         `json:"body"`
+		Error string         `json:"error"`
+	}{Paste: paste, Body: body, Error: errMsg}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal dead-letter entry for %s: %w", paste.Key, err)
+	}
+
+	if _, err := f.Write(append(raw, '\n')); err != nil {
+		return fmt.Errorf("write dead-letter entry for %s: %w", paste.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+// Package sink provides built-in pbmon.Sink adapters for common
+// message-queue and webhook destinations.
+package sink
+
+import pastebin "github.com/dutchcoders/gopastebin"
+
+// envelope is the JSON payload every adapter in this package publishes.
+type envelope struct {
+	Paste pastebin.Paste `json:"paste"`
+	Body  []byte         `json:"body"`
+}
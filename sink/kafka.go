@@ -0,0 +1,37 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka publishes each paste as a JSON message, keyed by paste key.
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+// NewKafka returns a Kafka sink that writes through writer.
+func NewKafka(writer *kafka.Writer) *Kafka {
+	return &Kafka{writer: writer}
+}
+
+// Publish writes paste and body as a message keyed by paste.Key.
+func (k *Kafka) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	payload, err := json.Marshal(envelope{Paste: paste, Body: body})
+	if err != nil {
+		return fmt.Errorf("marshal kafka payload for %s: %w", paste.Key, err)
+	}
+
+	err = k.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(paste.Key),
+		Value: payload,
+	})
+	if err != nil {
+		return fmt.Errorf("write kafka message for %s: %w", paste.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+	"github.com/nats-io/nats.go"
+)
+
+// NATS publishes each paste as a JSON message on a NATS subject.
+type NATS struct {
+	nc      *nats.Conn
+	subject string
+}
+
+// NewNATS returns a NATS sink that publishes to subject over nc.
+func NewNATS(nc *nats.Conn, subject string) *NATS {
+	return &NATS{nc: nc, subject: subject}
+}
+
+// Publish publishes paste and body to the configured subject.
+func (n *NATS) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	payload, err := json.Marshal(envelope{Paste: paste, Body: body})
+	if err != nil {
+		return fmt.Errorf("marshal nats payload for %s: %w", paste.Key, err)
+	}
+	if err := n.nc.Publish(n.subject, payload); err != nil {
+		return fmt.Errorf("publish %s to nats subject %s: %w", paste.Key, n.subject, err)
+	}
+	return nil
+}
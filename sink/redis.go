@@ -0,0 +1,38 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStream publishes each paste as a JSON entry on a Redis Stream.
+type RedisStream struct {
+	rdb    *redis.Client
+	stream string
+}
+
+// NewRedisStream returns a RedisStream sink that adds entries to stream.
+func NewRedisStream(rdb *redis.Client, stream string) *RedisStream {
+	return &RedisStream{rdb: rdb, stream: stream}
+}
+
+// Publish adds paste and body as an entry on the configured stream.
+func (r *RedisStream) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	payload, err := json.Marshal(envelope{Paste: paste, Body: body})
+	if err != nil {
+		return fmt.Errorf("marshal redis stream payload for %s: %w", paste.Key, err)
+	}
+
+	err = r.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.stream,
+		Values: map[string]interface{}{"payload": payload},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("xadd %s to redis stream %s: %w", paste.Key, r.stream, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+// Webhook POSTs each paste as JSON to a URL, signing the body with HMAC
+// when a secret is configured.
+type Webhook struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewWebhook returns a Webhook that POSTs to url, signing requests with
+// secret if non-empty.
+func NewWebhook(url string, secret []byte) *Webhook {
+	return &Webhook{url: url, secret: secret, client: http.DefaultClient}
+}
+
+// Publish POSTs paste and body to the configured URL.
+func (w *Webhook) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	payload, err := json.Marshal(envelope{Paste: paste, Body: body})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload for %s: %w", paste.Key, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build webhook request for %s: %w", paste.Key, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if len(w.secret) > 0 {
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(payload)
+		req.Header.Set("X-Pbmon-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook for %s: %w", paste.Key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", w.url, resp.StatusCode)
+	}
+	return nil
+}
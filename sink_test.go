@@ -0,0 +1,53 @@
+package pbmon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+type failingSink struct {
+	calls int
+	err   error
+}
+
+func (s *failingSink) Publish(ctx context.Context, paste pastebin.Paste, body []byte) error {
+	s.calls++
+	return s.err
+}
+
+func TestWithRetryRejectsZeroMaxAttempts(t *testing.T) {
+	fs := &failingSink{err: errors.New("down")}
+	s := WithRetry(fs, RetryPolicy{DeadLetterFile: "unused"})
+
+	if err := s.Publish(context.Background(), pastebin.Paste{Key: "k"}, []byte("body")); err == nil {
+		t.Fatal("Publish() error = nil, want non-nil for MaxAttempts = 0")
+	}
+	if fs.calls != 0 {
+		t.Fatalf("wrapped sink called %d times, want 0", fs.calls)
+	}
+}
+
+func TestWithRetryDeadLetter(t *testing.T) {
+	dlFile := t.TempDir() + "/dead-letter.jsonl"
+	fs := &failingSink{err: errors.New("down")}
+	s := WithRetry(fs, RetryPolicy{MaxAttempts: 2, DeadLetterFile: dlFile})
+
+	if err := s.Publish(context.Background(), pastebin.Paste{Key: "k"}, []byte("body")); err == nil {
+		t.Fatal("Publish() error = nil, want non-nil after exhausting attempts")
+	}
+	if fs.calls != 2 {
+		t.Fatalf("wrapped sink called %d times, want 2", fs.calls)
+	}
+
+	raw, err := os.ReadFile(dlFile)
+	if err != nil {
+		t.Fatalf("read dead-letter file: %v", err)
+	}
+	if len(raw) == 0 {
+		t.Fatal("dead-letter file is empty")
+	}
+}
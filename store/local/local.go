@@ -0,0 +1,124 @@
+// Package local provides filesystem-backed implementations of
+// pbmon.StateStore and pbmon.PasteStore, used as pbmon's default storage
+// when no other backend is configured.
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+const pasteIDLen = 9
+
+// validKey matches the shape of a pastebin.com paste key: a short run of
+// alphanumeric characters. paste.Key comes straight from the scraping
+// API response, so it's validated before being used as a path component
+// to rule out traversal via a key like "../../foo".
+var validKey = regexp.MustCompile(`^[A-Za-z0-9]{1,32}$`)
+
+// StateStore persists the last-seen paste key to a file on the local
+// filesystem.
+type StateStore struct {
+	f *os.File
+}
+
+// NewStateStore opens (creating it if necessary) the file at path for use
+// as state storage.
+func NewStateStore(path string) (*StateStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0744)
+	if err != nil {
+		return nil, fmt.Errorf("open state file %s: %w", path, err)
+	}
+	return &StateStore{f: f}, nil
+}
+
+// Load reads the last-seen paste key, returning an empty string if none
+// has been saved yet.
+func (s *StateStore) Load(ctx context.Context) (string, error) {
+	info, err := s.f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat state file: %w", err)
+	}
+	top := make([]byte, info.Size())
+
+	if _, err := s.f.ReadAt(top, 0); err != nil && err != io.EOF {
+		return "", fmt.Errorf("read state file: %w", err)
+	}
+	return string(top), nil
+}
+
+// Save overwrites the state file with key.
+func (s *StateStore) Save(ctx context.Context, key string) error {
+	if err := s.f.Truncate(0); err != nil {
+		return fmt.Errorf("truncate %s: %w", s.f.Name(), err)
+	}
+	if _, err := s.f.WriteAt([]byte(key), 0); err != nil {
+		return fmt.Errorf("save state to %s: %w", s.f.Name(), err)
+	}
+	return nil
+}
+
+// PasteStore archives paste bodies as plain files under dir, one file per
+// paste key.
+type PasteStore struct {
+	dir string
+}
+
+// NewPasteStore creates dir (if necessary) and returns a PasteStore that
+// archives pastes inside it.
+func NewPasteStore(dir string) (*PasteStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create archive directory %s: %w", dir, err)
+	}
+	return &PasteStore{dir: dir}, nil
+}
+
+// Put writes body to a file named after the paste key.
+func (s *PasteStore) Put(ctx context.Context, paste pastebin.Paste, body io.Reader) error {
+	if !validKey.MatchString(paste.Key) {
+		return fmt.Errorf("archive paste: invalid paste key %q", paste.Key)
+	}
+
+	f, err := os.Create(filepath.Join(s.dir, paste.Key))
+	if err != nil {
+		return fmt.Errorf("create archive file for %s: %w", paste.Key, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, body); err != nil {
+		return fmt.Errorf("archive paste %s: %w", paste.Key, err)
+	}
+	return nil
+}
+
+// DeleteOlderThan removes archived files whose modification time is
+// before cutoff, returning the number removed.
+func (s *PasteStore) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, fmt.Errorf("read archive directory %s: %w", s.dir, err)
+	}
+
+	var deleted int
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return deleted, fmt.Errorf("stat %s: %w", entry.Name(), err)
+		}
+		if info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+			return deleted, fmt.Errorf("remove %s: %w", entry.Name(), err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
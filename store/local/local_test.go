@@ -0,0 +1,87 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+func TestStateStoreLoadSave(t *testing.T) {
+	ss, err := NewStateStore(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("NewStateStore: %v", err)
+	}
+
+	got, err := ss.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load (before any Save): %v", err)
+	}
+	if got != "" {
+		t.Fatalf("Load (before any Save) = %q, want empty", got)
+	}
+
+	const key = "abc123456"
+	if err := ss.Save(context.Background(), key); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err = ss.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got != key {
+		t.Fatalf("Load() = %q, want %q", got, key)
+	}
+}
+
+func TestPasteStorePutRejectsInvalidKey(t *testing.T) {
+	ps, err := NewPasteStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewPasteStore: %v", err)
+	}
+
+	err = ps.Put(context.Background(), pastebin.Paste{Key: "../../evil"}, bytes.NewReader([]byte("x")))
+	if err == nil {
+		t.Fatal("Put() error = nil, want non-nil for a path-traversal key")
+	}
+}
+
+func TestPasteStoreDeleteOlderThan(t *testing.T) {
+	dir := t.TempDir()
+	ps, err := NewPasteStore(dir)
+	if err != nil {
+		t.Fatalf("NewPasteStore: %v", err)
+	}
+
+	if err := ps.Put(context.Background(), pastebin.Paste{Key: "oldpaste1"}, bytes.NewReader([]byte("old"))); err != nil {
+		t.Fatalf("Put(old): %v", err)
+	}
+	if err := ps.Put(context.Background(), pastebin.Paste{Key: "newpaste1"}, bytes.NewReader([]byte("new"))); err != nil {
+		t.Fatalf("Put(new): %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "oldpaste1"), old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	n, err := ps.DeleteOlderThan(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("DeleteOlderThan: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("DeleteOlderThan() = %d, want 1", n)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "oldpaste1")); !os.IsNotExist(err) {
+		t.Fatal("old paste file still exists after DeleteOlderThan")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "newpaste1")); err != nil {
+		t.Fatalf("new paste file missing: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package postgres provides PostgreSQL-backed implementations of
+// pbmon.StateStore and pbmon.PasteStore.
+//
+// It expects the following schema to already exist:
+//
+//	CREATE TABLE pbmon_state (
+//		name     TEXT PRIMARY KEY,
+//		top_key  TEXT NOT NULL
+//	);
+//
+//	CREATE TABLE pbmon_pastes (
+//		key        TEXT PRIMARY KEY,
+//		title      TEXT,
+//		user_name  TEXT,
+//		syntax     TEXT,
+//		url        TEXT,
+//		body       BYTEA NOT NULL,
+//		created_at TIMESTAMPTZ NOT NULL
+//	);
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+)
+
+// StateStore persists the last-seen paste key in the pbmon_state table,
+// keyed by name so several monitors can share one database.
+type StateStore struct {
+	db   *sql.DB
+	name string
+}
+
+// NewStateStore returns a StateStore that tracks state under name.
+func NewStateStore(db *sql.DB, name string) *StateStore {
+	return &StateStore{db: db, name: name}
+}
+
+// Load returns the last-saved key, or an empty string if none exists yet.
+func (s *StateStore) Load(ctx context.Context) (string, error) {
+	var key string
+	err := s.db.QueryRowContext(ctx, `SELECT top_key FROM pbmon_state WHERE name = $1`, s.name).Scan(&key)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("query state for %s: %w", s.name, err)
+	}
+	return key, nil
+}
+
+// Save upserts key as the last-seen paste key.
+func (s *StateStore) Save(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pbmon_state (name, top_key) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET top_key = EXCLUDED.top_key`, s.name, key)
+	if err != nil {
+		return fmt.Errorf("save state for %s: %w", s.name, err)
+	}
+	return nil
+}
+
+// PasteStore archives paste bodies in the pbmon_pastes table.
+type PasteStore struct {
+	db *sql.DB
+}
+
+// NewPasteStore returns a PasteStore backed by db.
+func NewPasteStore(db *sql.DB) *PasteStore {
+	return &PasteStore{db: db}
+}
+
+// Put inserts paste and body, ignoring pastes already archived.
+func (s *PasteStore) Put(ctx context.Context, paste pastebin.Paste, body io.Reader) error {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("read paste body for %s: %w", paste.Key, err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO pbmon_pastes (key, title, user_name, syntax, url, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, now())
+		ON CONFLICT (key) DO NOTHING`,
+		paste.Key, paste.Title, paste.User, paste.Syntax, paste.FullURL, raw)
+	if err != nil {
+		return fmt.Errorf("archive paste %s: %w", paste.Key, err)
+	}
+	return nil
+}
+
+// DeleteOlderThan removes archived pastes created before cutoff,
+// returning the number removed.
+func (s *PasteStore) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM pbmon_pastes WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("delete pastes older than %s: %w", cutoff, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("count deleted pastes: %w", err)
+	}
+	return int(n), nil
+}
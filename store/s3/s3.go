@@ -0,0 +1,154 @@
+// Package s3 provides implementations of pbmon.StateStore and
+// pbmon.PasteStore backed by S3-compatible object storage.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"time"
+
+	pastebin "github.com/dutchcoders/gopastebin"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// validKey matches the shape of a pastebin.com paste key: a short run of
+// alphanumeric characters. paste.Key comes straight from the scraping
+// API response, so it's validated before being joined into an object
+// key to rule out traversal via a key like "../../foo".
+var validKey = regexp.MustCompile(`^[A-Za-z0-9]{1,32}$`)
+
+// StateStore persists the last-seen paste key as a single object.
+type StateStore struct {
+	client *s3.Client
+	bucket string
+	key    string
+}
+
+// NewStateStore returns a StateStore that keeps its key object at
+// bucket/key.
+func NewStateStore(client *s3.Client, bucket, key string) *StateStore {
+	return &StateStore{client: client, bucket: bucket, key: key}
+}
+
+// Load returns the last-saved key, or an empty string if the object
+// doesn't exist yet.
+func (s *StateStore) Load(ctx context.Context) (string, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	defer out.Body.Close()
+
+	raw, err := io.ReadAll(out.Body)
+	if err != nil {
+		return "", fmt.Errorf("read s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	return string(raw), nil
+}
+
+// Save overwrites the state object with key.
+func (s *StateStore) Save(ctx context.Context, key string) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+		Body:   bytes.NewReader([]byte(key)),
+	})
+	if err != nil {
+		return fmt.Errorf("put s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	return nil
+}
+
+// PasteStore archives pastes as objects under bucket/prefix, one object per
+// paste key.
+type PasteStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewPasteStore returns a PasteStore that archives pastes under
+// bucket/prefix.
+func NewPasteStore(client *s3.Client, bucket, prefix string) *PasteStore {
+	return &PasteStore{client: client, bucket: bucket, prefix: prefix}
+}
+
+// Put uploads body as an object named after the paste key.
+func (s *PasteStore) Put(ctx context.Context, paste pastebin.Paste, body io.Reader) error {
+	if !validKey.MatchString(paste.Key) {
+		return fmt.Errorf("archive paste: invalid paste key %q", paste.Key)
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path.Join(s.prefix, paste.Key)),
+		Body:   body,
+		Metadata: map[string]string{
+			"title":  paste.Title,
+			"user":   paste.User,
+			"syntax": paste.Syntax,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put s3://%s/%s: %w", s.bucket, path.Join(s.prefix, paste.Key), err)
+	}
+	return nil
+}
+
+// DeleteOlderThan removes archived objects under the configured prefix
+// whose last-modified time is before cutoff, returning the number
+// removed.
+func (s *PasteStore) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	var deleted int
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return deleted, fmt.Errorf("list s3://%s/%s: %w", s.bucket, s.prefix, err)
+		}
+
+		var toDelete []types.ObjectIdentifier
+		for _, obj := range page.Contents {
+			if obj.LastModified == nil || obj.LastModified.After(cutoff) {
+				continue
+			}
+			toDelete = append(toDelete, types.ObjectIdentifier{Key: obj.Key})
+		}
+		if len(toDelete) == 0 {
+			continue
+		}
+
+		out, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &types.Delete{Objects: toDelete},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("delete objects in s3://%s/%s: %w", s.bucket, s.prefix, err)
+		}
+		// DeleteObjects reports per-object failures in Errors rather than
+		// failing the whole request; those objects were not removed.
+		deleted += len(toDelete) - len(out.Errors)
+	}
+
+	return deleted, nil
+}